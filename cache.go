@@ -0,0 +1,168 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+//
+// cache.go implements an optional, content-addressed cache for rendered
+// icons so repeat renders of the same icon at the same size are free.
+
+package oksvg
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RenderCache stores and retrieves rendered images by a caller-supplied
+// key, typically produced by RenderCacheKey. Get's second return value
+// reports whether img was found.
+type RenderCache interface {
+	Get(key string) (img image.Image, ok bool)
+	Put(key string, img image.Image)
+}
+
+// SetRenderCache installs cache as the RenderCache consulted by this
+// icon's AsImage*/SaveAs* methods. Passing nil disables caching.
+// sourceBytes should be the raw SVG source the icon was parsed from; it
+// is hashed into every cache key so icons parsed from different sources
+// never collide. Both are stored directly on the icon, so they're freed
+// along with it rather than pinning it in a package-global map.
+func (s *SvgIcon) SetRenderCache(cache RenderCache, sourceBytes []byte) {
+	s.renderCache = cache
+	if cache == nil {
+		s.renderSourceID = ""
+		return
+	}
+	h := sha256.Sum256(sourceBytes)
+	s.renderSourceID = hex.EncodeToString(h[:])
+}
+
+// RenderCacheKey computes the cache key for rendering this icon at w x h
+// with the given fit, align and background, from a hash of the icon's
+// source bytes (set via SetRenderCache), target size, transform and
+// render options.
+func (s *SvgIcon) RenderCacheKey(w, h int, fit FitMode, align Align, background string) string {
+	return fmt.Sprintf("%s:%dx%d:fit=%d:align=%d:xf=%v:bg=%s",
+		s.renderSourceID, w, h, fit, align, s.Transform, background)
+}
+
+// cachedAsImage consults the icon's RenderCache (if any) before falling
+// back to render(), and stores the result on a miss.
+func (s *SvgIcon) cachedAsImage(key string, render func() image.Image) image.Image {
+	if s.renderCache == nil {
+		return render()
+	}
+	if img, ok := s.renderCache.Get(key); ok {
+		return img
+	}
+	img := render()
+	s.renderCache.Put(key, img)
+	return img
+}
+
+// LRURenderCache is an in-memory RenderCache holding at most Capacity
+// entries, evicting the least recently used one once full. A Capacity of
+// zero or less means no limit.
+type LRURenderCache struct {
+	Capacity int
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key string
+	img image.Image
+}
+
+// NewLRURenderCache returns an LRURenderCache holding at most capacity
+// entries.
+func NewLRURenderCache(capacity int) *LRURenderCache {
+	return &LRURenderCache{
+		Capacity: capacity,
+		ll:       list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Get implements RenderCache.
+func (c *LRURenderCache) Get(key string) (image.Image, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*lruEntry).img, true
+}
+
+// Put implements RenderCache.
+func (c *LRURenderCache) Put(key string, img image.Image) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*lruEntry).img = img
+		c.ll.MoveToFront(el)
+		return
+	}
+	el := c.ll.PushFront(&lruEntry{key: key, img: img})
+	c.entries[key] = el
+	for c.Capacity > 0 && c.ll.Len() > c.Capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// DiskRenderCache is a RenderCache that writes rendered images as PNGs
+// under Dir, named by a hash of the cache key.
+type DiskRenderCache struct {
+	Dir string
+}
+
+// NewDiskRenderCache returns a DiskRenderCache rooted at dir, creating
+// it if necessary.
+func NewDiskRenderCache(dir string) (*DiskRenderCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskRenderCache{Dir: dir}, nil
+}
+
+func (c *DiskRenderCache) pathFor(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(h[:])+".png")
+}
+
+// Get implements RenderCache.
+func (c *DiskRenderCache) Get(key string) (image.Image, bool) {
+	f, err := os.Open(c.pathFor(key))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, false
+	}
+	return img, true
+}
+
+// Put implements RenderCache.
+func (c *DiskRenderCache) Put(key string, img image.Image) {
+	f, err := os.Create(c.pathFor(key))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	png.Encode(f, img)
+}
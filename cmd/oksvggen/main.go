@@ -0,0 +1,162 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+//
+// oksvggen reads a directory of .svg files and emits a single .go file
+// containing one function per icon that builds the equivalent SvgIcon
+// directly, with no XML parsing at runtime. Each emitted function
+// implements oksvg.Vector, so generated icons are used as:
+//
+//	icon := oksvg.NewIcon(generated.MyLogo)
+//	img := icon.AsImage()
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/timskillman/oksvg"
+)
+
+var (
+	inDir   = flag.String("in", ".", "directory of .svg files to compile")
+	outFile = flag.String("out", "icons_generated.go", "output .go file path")
+	pkg     = flag.String("pkg", "generated", "package name for the output file")
+)
+
+func main() {
+	flag.Parse()
+
+	entries, err := os.ReadDir(*inDir)
+	if err != nil {
+		log.Fatalf("oksvggen: read %s: %v", *inDir, err)
+	}
+
+	var icons []iconSource
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".svg") {
+			continue
+		}
+		path := filepath.Join(*inDir, e.Name())
+		src, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("oksvggen: %s: %v", path, err)
+		}
+		if usesGradient(src) {
+			// ReadIcon doesn't parse <linearGradient>/<radialGradient> at
+			// all yet, and EncodeStyle/EncodePathOps only serialize solid
+			// FillColor/LineColor, so silently generating this icon would
+			// drop its gradients and render it flat.
+			log.Fatalf("oksvggen: %s: uses a <linearGradient>/<radialGradient>, which this generator cannot serialize yet", path)
+		}
+		icon, err := oksvg.ReadIcon(bytes.NewReader(src))
+		if err != nil {
+			log.Fatalf("oksvggen: parse %s: %v", path, err)
+		}
+		icons = append(icons, iconSource{
+			FuncName: exportedName(e.Name()),
+			Icon:     icon,
+		})
+	}
+
+	sort.Slice(icons, func(i, j int) bool { return icons[i].FuncName < icons[j].FuncName })
+
+	var buf bytes.Buffer
+	if err := genTemplate.Execute(&buf, struct {
+		Package string
+		Icons   []iconSource
+	}{*pkg, icons}); err != nil {
+		log.Fatalf("oksvggen: render template: %v", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		// Write the unformatted source so it can still be inspected.
+		src = buf.Bytes()
+		log.Printf("oksvggen: gofmt failed, writing unformatted output: %v", err)
+	}
+
+	if err := os.WriteFile(*outFile, src, 0o644); err != nil {
+		log.Fatalf("oksvggen: write %s: %v", *outFile, err)
+	}
+	fmt.Printf("oksvggen: wrote %d icon(s) to %s\n", len(icons), *outFile)
+}
+
+// iconSource pairs a generated function name with the parsed icon it
+// should reproduce.
+type iconSource struct {
+	FuncName string
+	Icon     *oksvg.SvgIcon
+}
+
+// usesGradient reports whether src's raw markup defines a gradient.
+// ReadIcon has no gradient support to consult instead (see its doc
+// comment), so this is a source-text check rather than a parsed one.
+func usesGradient(src []byte) bool {
+	return bytes.Contains(src, []byte("<linearGradient")) || bytes.Contains(src, []byte("<radialGradient"))
+}
+
+// exportedName turns an SVG file's base name (e.g. "my-logo.svg") into a
+// valid exported Go identifier ("MyLogo").
+func exportedName(fileName string) string {
+	base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	var sb strings.Builder
+	upperNext := true
+	for _, r := range base {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if upperNext {
+				r = unicode.ToUpper(r)
+				upperNext = false
+			}
+			sb.WriteRune(r)
+		default:
+			upperNext = true
+		}
+	}
+	name := sb.String()
+	if name == "" {
+		name = "Icon"
+	}
+	if unicode.IsDigit(rune(name[0])) {
+		name = "Icon" + name
+	}
+	return name
+}
+
+var genTemplate = template.Must(template.New("oksvggen").Funcs(template.FuncMap{
+	"pathOps": oksvg.EncodePathOps,
+	"style":   oksvg.EncodeStyle,
+}).Parse(`// Code generated by cmd/oksvggen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"image/color"
+
+	"github.com/timskillman/oksvg"
+	"github.com/timskillman/rasterx"
+)
+
+{{range .Icons}}
+// {{.FuncName}} builds the icon compiled from its source SVG file.
+var {{.FuncName}} = oksvg.VectorFunc(func(icon *oksvg.SvgIcon) {
+	icon.ViewBox.X, icon.ViewBox.Y = {{.Icon.ViewBox.X}}, {{.Icon.ViewBox.Y}}
+	icon.ViewBox.W, icon.ViewBox.H = {{.Icon.ViewBox.W}}, {{.Icon.ViewBox.H}}
+	icon.Transform = rasterx.Identity
+{{range $i, $p := .Icon.SVGPaths}}
+	icon.SVGPaths = append(icon.SVGPaths, oksvg.SvgPath{
+		Path:  rasterx.Path{ {{pathOps $p.Path}} },
+		Style: {{style $p.Style}},
+	})
+{{end}}
+})
+{{end}}
+`))
@@ -0,0 +1,52 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+//
+// codegen.go provides the literal-source encoders used by cmd/oksvggen
+// to serialize a parsed SvgPath into Go source.
+
+package oksvg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timskillman/rasterx"
+)
+
+// EncodePathOps renders a rasterx.Path as a comma-separated list of its
+// underlying float64 op codes/operands, suitable for splicing into a
+// rasterx.Path{...} composite literal.
+func EncodePathOps(p rasterx.Path) string {
+	parts := make([]string, len(p))
+	for i, v := range p {
+		parts[i] = fmt.Sprintf("%g", v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// EncodeStyle renders a PathStyle as a Go composite literal.
+//
+// Limitation: this only captures solid FillColor/LineColor plus opacity
+// and line width. cmd/oksvggen scans each SVG's raw source for a
+// <linearGradient>/<radialGradient> before calling this and refuses to
+// generate that icon rather than silently emit a flattened gradient, so
+// a gradient-using SVG fails the generator run instead of producing
+// visibly wrong output. Per-path/root transforms aren't captured by this
+// package's parser at all yet, so there is nothing for EncodeStyle to
+// drop there; extending the parser to record transform attributes, and
+// this encoder to serialize them, is tracked as follow-up work.
+func EncodeStyle(style PathStyle) string {
+	return fmt.Sprintf("oksvg.PathStyle{ FillOpacity: %g, LineOpacity: %g, LineWidth: %g, FillColor: %s, LineColor: %s }",
+		style.FillOpacity, style.LineOpacity, style.LineWidth,
+		encodeColor(style.FillColor), encodeColor(style.LineColor))
+}
+
+// encodeColor renders a color.Color as an rgba-component composite
+// literal, since color.Color itself is an interface and cannot be
+// reproduced as a literal directly.
+func encodeColor(c interface{ RGBA() (r, g, b, a uint32) }) string {
+	if c == nil {
+		return "nil"
+	}
+	r, g, b, a := c.RGBA()
+	return fmt.Sprintf("color.RGBA64{R: %d, G: %d, B: %d, A: %d}", r, g, b, a)
+}
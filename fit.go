@@ -0,0 +1,178 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+//
+// fit.go implements aspect-ratio-aware target sizing, modeled on SVG's
+// preserveAspectRatio attribute.
+
+package oksvg
+
+import (
+	"image"
+	"strings"
+
+	"github.com/timskillman/rasterx"
+)
+
+// FitMode controls how the viewBox is fitted into a target rectangle.
+type FitMode int
+
+const (
+	// FitMeet scales the viewBox uniformly so that it fits entirely
+	// within the target rectangle (letterboxing as needed). This is the
+	// zero value, matching SVG's own default of preserveAspectRatio
+	// "xMidYMid meet" for an icon with no parsed preserveAspectRatio.
+	FitMeet FitMode = iota
+	// FitNone stretches the viewBox to fill the target, distorting the
+	// aspect ratio if necessary. This is the behavior of SetTarget.
+	FitNone
+	// FitSlice scales the viewBox uniformly so that it entirely covers
+	// the target rectangle, cropping any overflow.
+	FitSlice
+	// FitWidth scales the viewBox so its width matches the target width,
+	// preserving aspect ratio.
+	FitWidth
+	// FitHeight scales the viewBox so its height matches the target
+	// height, preserving aspect ratio.
+	FitHeight
+)
+
+// Align selects which part of the viewBox is aligned to the target
+// rectangle once scaled, mirroring the x<Min|Mid|Max>Y<Min|Mid|Max>
+// tokens of SVG's preserveAspectRatio attribute.
+type Align int
+
+const (
+	AlignXMidYMid Align = iota
+	AlignNone
+	AlignXMinYMin
+	AlignXMidYMin
+	AlignXMaxYMin
+	AlignXMinYMid
+	AlignXMaxYMid
+	AlignXMinYMax
+	AlignXMidYMax
+	AlignXMaxYMax
+)
+
+// parseAlign maps the alignment token of a preserveAspectRatio attribute
+// (e.g. "xMinYMid") to an Align value. Unrecognized tokens default to
+// AlignXMidYMid, matching the SVG spec's default.
+func parseAlign(tok string) Align {
+	switch tok {
+	case "none":
+		return AlignNone
+	case "xMinYMin":
+		return AlignXMinYMin
+	case "xMidYMin":
+		return AlignXMidYMin
+	case "xMaxYMin":
+		return AlignXMaxYMin
+	case "xMinYMid":
+		return AlignXMinYMid
+	case "xMaxYMid":
+		return AlignXMaxYMid
+	case "xMinYMax":
+		return AlignXMinYMax
+	case "xMidYMax":
+		return AlignXMidYMax
+	case "xMaxYMax":
+		return AlignXMaxYMax
+	default:
+		return AlignXMidYMid
+	}
+}
+
+// parsePreserveAspectRatio parses a preserveAspectRatio attribute value
+// ("xMidYMid meet", "none", "xMinYMax slice", ...) into an Align and a
+// FitMode. A blank value returns the SVG defaults (xMidYMid, FitMeet).
+func parsePreserveAspectRatio(v string) (Align, FitMode) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return AlignXMidYMid, FitMeet
+	}
+	fields := strings.Fields(v)
+	// The optional leading "defer" keyword has no effect on oksvg's
+	// single-pass parser, so it is simply skipped.
+	if len(fields) > 0 && fields[0] == "defer" {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return AlignXMidYMid, FitMeet
+	}
+	align := parseAlign(fields[0])
+	if align == AlignNone {
+		return AlignNone, FitNone
+	}
+	fit := FitMeet
+	if len(fields) > 1 && fields[1] == "slice" {
+		fit = FitSlice
+	}
+	return align, fit
+}
+
+// SetTargetAspect sets the Transform matrix to draw the icon's viewBox
+// within the rectangle (x, y, w, h), preserving aspect ratio according to
+// fit and align. Unlike SetTarget, which stretches to fill the rectangle
+// exactly, FitMeet and FitSlice compute a single uniform scale from the
+// viewBox and target dimensions and then translate per align so the
+// scaled viewBox is positioned within (or around) the target.
+func (s *SvgIcon) SetTargetAspect(x, y, w, h float64, fit FitMode, align Align) {
+	vbW, vbH := s.ViewBox.W, s.ViewBox.H
+	if vbW == 0 || vbH == 0 {
+		s.SetTarget(x, y, w, h)
+		return
+	}
+
+	var scaleW, scaleH float64
+	switch fit {
+	case FitNone:
+		s.SetTarget(x, y, w, h)
+		return
+	case FitWidth:
+		scaleW = w / vbW
+		scaleH = scaleW
+	case FitHeight:
+		scaleH = h / vbH
+		scaleW = scaleH
+	case FitSlice:
+		sc := w / vbW
+		if hs := h / vbH; hs > sc {
+			sc = hs
+		}
+		scaleW, scaleH = sc, sc
+	case FitMeet:
+		fallthrough
+	default:
+		sc := w / vbW
+		if hs := h / vbH; hs < sc {
+			sc = hs
+		}
+		scaleW, scaleH = sc, sc
+	}
+
+	scaledW := vbW * scaleW
+	scaledH := vbH * scaleH
+	tx, ty := x, y
+	switch align {
+	case AlignXMidYMid, AlignXMidYMin, AlignXMidYMax:
+		tx += (w - scaledW) / 2
+	case AlignXMaxYMin, AlignXMaxYMid, AlignXMaxYMax:
+		tx += w - scaledW
+	}
+	switch align {
+	case AlignXMidYMid, AlignXMinYMid, AlignXMaxYMid:
+		ty += (h - scaledH) / 2
+	case AlignXMinYMax, AlignXMidYMax, AlignXMaxYMax:
+		ty += h - scaledH
+	}
+
+	s.Transform = rasterx.Identity.Translate(tx-s.ViewBox.X*scaleW, ty-s.ViewBox.Y*scaleH).Scale(scaleW, scaleH)
+}
+
+// AsImageFit returns the SvgIcon as an image of exactly width x height,
+// scaled and aligned according to fit and align rather than distorting
+// the viewBox to fill the target, as AsImageResize does.
+func (s *SvgIcon) AsImageFit(width, height int, fit FitMode, align Align) image.Image {
+	s.SetTargetAspect(0, 0, float64(width), float64(height), fit, align)
+	key := s.RenderCacheKey(width, height, fit, align, "")
+	return s.cachedAsImage(key, func() image.Image { return s.asImage(width, height) })
+}
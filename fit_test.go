@@ -0,0 +1,41 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+
+package oksvg
+
+import "testing"
+
+func TestFitModeZeroValueIsMeet(t *testing.T) {
+	var icon SvgIcon
+	if icon.PreserveAspectRatio.Fit != FitMeet {
+		t.Errorf("zero-value PreserveAspectRatio.Fit = %v, want FitMeet", icon.PreserveAspectRatio.Fit)
+	}
+	if icon.PreserveAspectRatio.Align != AlignXMidYMid {
+		t.Errorf("zero-value PreserveAspectRatio.Align = %v, want AlignXMidYMid", icon.PreserveAspectRatio.Align)
+	}
+}
+
+func TestAsImageResizeHonorsPreserveAspectRatioByDefault(t *testing.T) {
+	meet := &SvgIcon{}
+	meet.ViewBox.W, meet.ViewBox.H = 100, 50
+	meet.PreserveAspectRatio.Align, meet.PreserveAspectRatio.Fit = AlignXMidYMid, FitMeet
+	meet.AsImageResize(100, 100)
+
+	distorted := &SvgIcon{}
+	distorted.ViewBox.W, distorted.ViewBox.H = 100, 50
+	distorted.SetTarget(0, 0, 100, 100)
+
+	// AsImageResize's default (FitMeet) must produce a different transform
+	// than the distorting SetTarget for a viewBox whose aspect ratio
+	// doesn't match the target, proving it no longer always stretches to
+	// fill.
+	if meet.Transform == distorted.Transform {
+		t.Errorf("AsImageResize produced the same Transform as distorting SetTarget; PreserveAspectRatio default not honored")
+	}
+}
+
+func TestParsePreserveAspectRatioDefault(t *testing.T) {
+	align, fit := parsePreserveAspectRatio("")
+	if align != AlignXMidYMid || fit != FitMeet {
+		t.Errorf("parsePreserveAspectRatio(\"\") = %v, %v, want AlignXMidYMid, FitMeet", align, fit)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+//
+// parse.go implements ReadIcon, a real (if still partial) SVG parser that
+// wires the root-attribute metadata in svginfo.go and the fill/stroke
+// color-token detection in theme.go into actually-parsed elements, rather
+// than leaving AddPath/ApplyTo as caller-only helpers.
+
+package oksvg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/timskillman/rasterx"
+	"golang.org/x/image/math/fixed"
+)
+
+// ReadIcon parses r as an SVG document and returns the resulting SvgIcon.
+//
+// Scope: ReadIcon understands the root <svg> element's viewBox/width/
+// height/preserveAspectRatio (via ReadIconInfo and SvgInfo.ApplyTo) plus
+// <rect> and <circle> child elements, appended through AddPath so a
+// fill/stroke of "currentColor" or "var(--x)" is recorded as a ColorToken
+// for DrawThemed. It does not parse the <path d="..."> mini-language or
+// gradients; those are a much larger undertaking and are left for a
+// fuller parser to add on top of this one.
+func ReadIcon(r io.Reader) (*SvgIcon, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := ReadIconInfo(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	icon := &SvgIcon{}
+	info.ApplyTo(icon)
+	icon.Titles = info.Titles
+	icon.Descriptions = info.Descriptions
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "rect":
+			icon.addRect(start.Attr)
+		case "circle":
+			icon.addCircle(start.Attr)
+		}
+	}
+	return icon, nil
+}
+
+// attrValue returns the value of the attribute named name in attrs, and
+// whether it was present.
+func attrValue(attrs []xml.Attr, name string) (string, bool) {
+	for _, a := range attrs {
+		if a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func attrFloat(attrs []xml.Attr, name string) float64 {
+	v, ok := attrValue(attrs, name)
+	if !ok {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	return f
+}
+
+// fx converts a user-unit coordinate to a fixed.Int26_6, as rasterx.Path's
+// builder methods expect.
+func fx(v float64) fixed.Int26_6 {
+	return fixed.Int26_6(v * 64)
+}
+
+func fxPoint(x, y float64) fixed.Point26_6 {
+	return fixed.Point26_6{X: fx(x), Y: fx(y)}
+}
+
+// parseColorAttr bakes in a concrete color for a fill/stroke attribute
+// value that isn't a deferred ColorToken (plain hex colors, or anything
+// AddPath didn't recognize via ParseColorToken). It intentionally only
+// understands "#rrggbb"/"#rgb"; named CSS colors fall back to black, and
+// currentColor/var() references render black too until DrawThemed
+// resolves their recorded token against a Theme.
+func parseColorAttr(value string) color.Color {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "#") {
+		return color.Black
+	}
+	hex := value[1:]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.Black
+	}
+	r, err1 := strconv.ParseUint(hex[0:2], 16, 8)
+	g, err2 := strconv.ParseUint(hex[2:4], 16, 8)
+	b, err3 := strconv.ParseUint(hex[4:6], 16, 8)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return color.Black
+	}
+	return color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}
+}
+
+// addRect appends the path for a <rect> element, wiring its fill/stroke
+// through AddPath.
+func (s *SvgIcon) addRect(attrs []xml.Attr) {
+	x, y := attrFloat(attrs, "x"), attrFloat(attrs, "y")
+	w, h := attrFloat(attrs, "width"), attrFloat(attrs, "height")
+
+	var path rasterx.Path
+	path.Start(fxPoint(x, y))
+	path.Line(fxPoint(x+w, y))
+	path.Line(fxPoint(x+w, y+h))
+	path.Line(fxPoint(x, y+h))
+	path.Stop(true)
+
+	s.addShape(path, attrs)
+}
+
+// addCircle appends the path for a <circle> element, approximated with
+// four cubic Bezier arcs, wiring its fill/stroke through AddPath.
+func (s *SvgIcon) addCircle(attrs []xml.Attr) {
+	cx, cy := attrFloat(attrs, "cx"), attrFloat(attrs, "cy")
+	rad := attrFloat(attrs, "r")
+	// kappa approximates a quarter circle with a cubic Bezier to within
+	// about 0.03% of true radius, the standard constant for this.
+	const kappa = 0.5522847498
+
+	var path rasterx.Path
+	path.Start(fxPoint(cx+rad, cy))
+	path.CubeBezier(fxPoint(cx+rad, cy+rad*kappa), fxPoint(cx+rad*kappa, cy+rad), fxPoint(cx, cy+rad))
+	path.CubeBezier(fxPoint(cx-rad*kappa, cy+rad), fxPoint(cx-rad, cy+rad*kappa), fxPoint(cx-rad, cy))
+	path.CubeBezier(fxPoint(cx-rad, cy-rad*kappa), fxPoint(cx-rad*kappa, cy-rad), fxPoint(cx, cy-rad))
+	path.CubeBezier(fxPoint(cx+rad*kappa, cy-rad), fxPoint(cx+rad, cy-rad*kappa), fxPoint(cx+rad, cy))
+	path.Stop(true)
+
+	s.addShape(path, attrs)
+}
+
+// addShape bakes in path's fill/stroke colors from attrs and appends it
+// via AddPath, so a fill/stroke of "currentColor" or "var(--x)" is
+// recorded as a ColorToken for DrawThemed instead of baked in as black.
+func (s *SvgIcon) addShape(path rasterx.Path, attrs []xml.Attr) {
+	fillAttr, hasFill := attrValue(attrs, "fill")
+	if !hasFill {
+		fillAttr = "#000000"
+	}
+	strokeAttr, _ := attrValue(attrs, "stroke")
+
+	style := PathStyle{
+		FillOpacity: 1,
+		LineOpacity: 1,
+		LineWidth:   attrFloat(attrs, "stroke-width"),
+		FillColor:   parseColorAttr(fillAttr),
+		LineColor:   parseColorAttr(strokeAttr),
+	}
+	s.AddPath(SvgPath{Path: path, Style: style}, fillAttr, strokeAttr)
+}
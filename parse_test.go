@@ -0,0 +1,62 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+
+package oksvg
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestReadIconAppliesRootAttrsRegardlessOfOrder(t *testing.T) {
+	// width/height appear before viewBox, same as the order-independence
+	// this fixes in ReadIconInfo/parseSvgInfoAttrs.
+	const src = `<svg width="200px" height="25%" viewBox="0 0 100 50"><rect x="0" y="0" width="10" height="10"/></svg>`
+
+	icon, err := ReadIcon(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadIcon: %v", err)
+	}
+
+	w, h, hasW, hasH := icon.IntrinsicSize()
+	if !hasW || w != 200 {
+		t.Errorf("width = %v, hasWidth = %v, want 200, true", w, hasW)
+	}
+	if !hasH || h != 12.5 {
+		t.Errorf("height = %v, hasHeight = %v, want 12.5, true", h, hasH)
+	}
+	if len(icon.SVGPaths) != 1 {
+		t.Fatalf("len(SVGPaths) = %d, want 1", len(icon.SVGPaths))
+	}
+}
+
+func TestReadIconWiresCurrentColorIntoDrawThemed(t *testing.T) {
+	const src = `<svg viewBox="0 0 10 10"><rect x="0" y="0" width="10" height="10" fill="currentColor"/></svg>`
+
+	icon, err := ReadIcon(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadIcon: %v", err)
+	}
+	if len(icon.colorTokens) != 1 {
+		t.Fatalf("colorTokens = %+v, want one entry recorded by AddPath during parsing", icon.colorTokens)
+	}
+
+	icon.SetTarget(0, 0, 10, 10)
+	red := icon.AsImageThemed(10, 10, Theme{CurrentColor: color.RGBA{R: 255, A: 255}})
+	blue := icon.AsImageThemed(10, 10, Theme{CurrentColor: color.RGBA{B: 255, A: 255}})
+	if imagesIdentical(red, blue) {
+		t.Errorf("a currentColor fill parsed by ReadIcon rendered identically under two different themes")
+	}
+}
+
+func TestReadIconParsesRectAndCircle(t *testing.T) {
+	const src = `<svg viewBox="0 0 100 100"><rect x="0" y="0" width="10" height="10"/><circle cx="50" cy="50" r="5"/></svg>`
+
+	icon, err := ReadIcon(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadIcon: %v", err)
+	}
+	if len(icon.SVGPaths) != 2 {
+		t.Fatalf("len(SVGPaths) = %d, want 2", len(icon.SVGPaths))
+	}
+}
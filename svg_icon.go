@@ -8,8 +8,6 @@ package oksvg
 import (
 	"bufio"
 	"image"
-	"image/jpeg"
-	"image/png"
 	"os"
 
 	"github.com/timskillman/rasterx"
@@ -25,6 +23,47 @@ type SvgIcon struct {
 	SVGPaths     []SvgPath
 	Transform    rasterx.Matrix2D
 	classes      map[string]styleAttribute
+	// PreserveAspectRatio is the align/fit pair parsed from the root
+	// <svg> element's preserveAspectRatio attribute, set via
+	// SvgInfo.ApplyTo. Its zero value is (AlignXMidYMid, FitMeet),
+	// matching the SVG spec's default, so an icon built without ever
+	// calling ApplyTo still reports the spec-correct default here.
+	//
+	// AsImageResize (and the SaveAs*Resized helpers built on it) render
+	// through this field by default, via SetTargetAspect, so a parsed
+	// icon's own preserveAspectRatio is honored unless a caller overrides
+	// it explicitly with AsImageFit/SetTargetAspect or the distorting
+	// SetTarget. Draw itself still just renders through whatever
+	// Transform is currently set, so it honors whichever of those the
+	// caller used last.
+	PreserveAspectRatio struct {
+		Align Align
+		Fit   FitMode
+	}
+	// width, height hold the root <svg> element's parsed width/height
+	// attributes (resolved to user units), distinct from ViewBox.W/H.
+	// They are set by the attribute parser and read back by
+	// IntrinsicSize.
+	width, height       float64
+	widthSet, heightSet bool
+	// colorTokens holds deferred fill/stroke color references (set via
+	// SetPathColorTokens) keyed by index into SVGPaths, resolved against
+	// a Theme by DrawThemed.
+	colorTokens map[int]pathColorTokens
+	// renderCache and renderSourceID back SetRenderCache/RenderCacheKey.
+	// They live on the icon itself, not a package-global map keyed by
+	// *SvgIcon, so they're freed along with the icon instead of pinning
+	// it in memory for the life of the process.
+	renderCache    RenderCache
+	renderSourceID string
+}
+
+// setPreserveAspectRatio records the parsed preserveAspectRatio attribute
+// of the root <svg> element. It is called from SvgInfo.ApplyTo, which
+// the full icon parser runs against the same source right after parsing
+// the root <svg> element (see svginfo.go).
+func (s *SvgIcon) setPreserveAspectRatio(v string) {
+	s.PreserveAspectRatio.Align, s.PreserveAspectRatio.Fit = parsePreserveAspectRatio(v)
 }
 
 // Draw the compiled SVG icon into the GraphicContext.
@@ -46,7 +85,11 @@ func (s *SvgIcon) SetTarget(x, y, w, h float64) {
 // **NEW** Returns the SvgIcon as an image set to a given width and height.
 // However, if width is set to -1 then the original width of the SvgIcon is used.
 // If the height is set to -1 then the SvgIcon maintains its aspect ratio even when
-// an arbitrary width is set
+// an arbitrary width is set.
+//
+// The viewBox is fitted into width x height according to the icon's parsed
+// PreserveAspectRatio (see its doc comment) rather than being stretched to
+// fill it exactly; call AsImageFit or SetTarget directly to override that.
 func (s *SvgIcon) AsImageResize(width, height float64) image.Image {
 	if width < 1 {
 		width = s.ViewBox.W
@@ -55,13 +98,22 @@ func (s *SvgIcon) AsImageResize(width, height float64) image.Image {
 		sc := width / s.ViewBox.W
 		height = s.ViewBox.H * sc
 	}
-	s.SetTarget(0, 0, width, height)
-	return s.asImage(int(width), int(height))
+	s.SetTargetAspect(0, 0, width, height, s.PreserveAspectRatio.Fit, s.PreserveAspectRatio.Align)
+	w, h := int(width), int(height)
+	key := s.RenderCacheKey(w, h, s.PreserveAspectRatio.Fit, s.PreserveAspectRatio.Align, "")
+	return s.cachedAsImage(key, func() image.Image { return s.asImage(w, h) })
 }
 
 // **NEW** Returns the SvgIcon as an image set to its original width and height.
 func (s *SvgIcon) AsImage() image.Image {
-	return s.asImage(int(s.ViewBox.W), int(s.ViewBox.H))
+	return s.cachedAsImageSized(int(s.ViewBox.W), int(s.ViewBox.H))
+}
+
+// cachedAsImageSized routes through the icon's RenderCache, if one is
+// installed via SetRenderCache, before falling back to asImage.
+func (s *SvgIcon) cachedAsImageSized(w, h int) image.Image {
+	key := s.RenderCacheKey(w, h, FitNone, AlignNone, "")
+	return s.cachedAsImage(key, func() image.Image { return s.asImage(w, h) })
 }
 
 func (s *SvgIcon) asImage(w, h int) image.Image {
@@ -98,32 +150,30 @@ func (s *SvgIcon) SaveAsJpeg(filePath string) error {
 	return s.saveImage(filePath, s.AsImage(), false)
 }
 
+// saveImage writes m to filePath as PNG or JPEG. It is kept for the
+// older SaveAsPng*/SaveAsJpeg* helpers above; new callers should prefer
+// Save, which dispatches to the Encoder registry in encode.go, or SaveAs
+// with an explicit Encoder.
 func (s *SvgIcon) saveImage(filePath string, m image.Image, asPng bool) error {
-	// Create the file
+	enc := PNGEncoder
+	if !asPng {
+		enc = JPEGEncoder{}
+	}
+	return s.saveImageAs(filePath, m, enc)
+}
+
+func (s *SvgIcon) saveImageAs(filePath string, m image.Image, enc Encoder) error {
 	f, err := os.Create(filePath)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
-	// Create Writer from file
 	w := bufio.NewWriter(f)
-
-	// Write the image as either PNG or JPEG into the buffer
-	if asPng {
-		if err := png.Encode(w, m); err != nil {
-			return err
-		}
-	} else {
-		if err := jpeg.Encode(w, m, nil); err != nil {
-			return err
-		}
-	}
-
-	if err := w.Flush(); err != nil {
+	if err := enc.Encode(w, m); err != nil {
 		return err
 	}
-	return nil
+	return w.Flush()
 }
 
 // **NEW** Transform the SvgIcon with the supplied transformation matrix2D
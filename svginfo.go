@@ -0,0 +1,213 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+//
+// svginfo.go implements cheap access to an SVG's intrinsic dimensions,
+// without building the full path tree.
+
+package oksvg
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// IntrinsicSize returns the icon's parsed width/height attributes,
+// resolved against the viewBox for unit suffixes (px, pt, mm, in, %).
+// hasWidth/hasHeight report whether the attribute was present in the
+// source; when absent, w/h fall back to the viewBox dimensions, matching
+// the SVG spec's behavior of treating a missing width/height as 100%.
+//
+// The underlying width/height fields are populated by SvgInfo.ApplyTo,
+// so a fully parsed icon reports accurate values once the parser that
+// built it has called ReadIconInfo + ApplyTo on the same source (see
+// ApplyTo's doc comment). Without that call, IntrinsicSize reports the
+// viewBox dimensions for both, same as an SVG with no width/height
+// attributes at all.
+func (s *SvgIcon) IntrinsicSize() (w, h float64, hasWidth, hasHeight bool) {
+	w, hasWidth = s.width, s.widthSet
+	h, hasHeight = s.height, s.heightSet
+	if !hasWidth {
+		w = s.ViewBox.W
+	}
+	if !hasHeight {
+		h = s.ViewBox.H
+	}
+	return w, h, hasWidth, hasHeight
+}
+
+// SvgInfo holds the root-attribute metadata returned by ReadIconInfo.
+type SvgInfo struct {
+	ViewBox             struct{ X, Y, W, H float64 }
+	Width, Height       float64
+	HasWidth, HasHeight bool
+	PreserveAspectRatio string
+	Titles              []string
+	Descriptions        []string
+}
+
+// ReadIconInfo parses only the root <svg> element's attributes (viewBox,
+// width, height, preserveAspectRatio) and any immediate title/desc
+// children, then stops — it never builds the path tree. This lets
+// callers probe an SVG's pixel dimensions cheaply before committing to a
+// full ReadIcon parse and render.
+func ReadIconInfo(r io.Reader) (*SvgInfo, error) {
+	decoder := xml.NewDecoder(r)
+	info := &SvgInfo{}
+	depth := 0
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch t.Name.Local {
+			case "svg":
+				if depth != 1 {
+					continue
+				}
+				parseSvgInfoAttrs(info, t.Attr)
+			case "title":
+				if depth == 2 {
+					if txt, err := readCharData(decoder); err == nil {
+						info.Titles = append(info.Titles, txt)
+						// readCharData consumed </title>.
+						depth--
+					}
+				}
+			case "desc":
+				if depth == 2 {
+					if txt, err := readCharData(decoder); err == nil {
+						info.Descriptions = append(info.Descriptions, txt)
+						// readCharData consumed </desc>.
+						depth--
+					}
+				}
+			}
+		case xml.EndElement:
+			depth--
+			if t.Name.Local == "svg" && depth == 0 {
+				return info, nil
+			}
+		}
+	}
+	return info, nil
+}
+
+// ApplyTo copies the root-attribute metadata in info onto icon: its
+// ViewBox, the width/height pair read back by IntrinsicSize, and its
+// PreserveAspectRatio default. The full SvgIcon parser calls this with
+// the SvgInfo obtained from ReadIconInfo on the same source immediately
+// after parsing the root <svg> element, so the one attribute-resolution
+// codepath above backs both the cheap, path-tree-free probe and the
+// fully parsed icon.
+func (info *SvgInfo) ApplyTo(icon *SvgIcon) {
+	icon.ViewBox = info.ViewBox
+	icon.width, icon.widthSet = info.Width, info.HasWidth
+	icon.height, icon.heightSet = info.Height, info.HasHeight
+	icon.setPreserveAspectRatio(info.PreserveAspectRatio)
+}
+
+// readCharData reads the character data immediately following the
+// current start element, stopping at its end element.
+func readCharData(decoder *xml.Decoder) (string, error) {
+	var sb strings.Builder
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return sb.String(), err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			sb.Write(t)
+		case xml.EndElement:
+			return sb.String(), nil
+		}
+	}
+}
+
+// parseSvgInfoAttrs parses the root <svg> element's attributes into info.
+// viewBox is resolved in its own pass before width/height, since width/
+// height percentages are resolved against it and XML attribute order is
+// not guaranteed — "<svg width=\"50%\" viewBox=\"...\">" is exactly as
+// valid as the reverse.
+func parseSvgInfoAttrs(info *SvgInfo, attrs []xml.Attr) {
+	for _, attr := range attrs {
+		if attr.Name.Local == "viewBox" {
+			fields := strings.Fields(attr.Value)
+			if len(fields) == 4 {
+				info.ViewBox.X, _ = strconv.ParseFloat(fields[0], 64)
+				info.ViewBox.Y, _ = strconv.ParseFloat(fields[1], 64)
+				info.ViewBox.W, _ = strconv.ParseFloat(fields[2], 64)
+				info.ViewBox.H, _ = strconv.ParseFloat(fields[3], 64)
+			}
+			break
+		}
+	}
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "width":
+			if v, ok := resolveLength(attr.Value, info.ViewBox.W); ok {
+				info.Width, info.HasWidth = v, true
+			}
+		case "height":
+			if v, ok := resolveLength(attr.Value, info.ViewBox.H); ok {
+				info.Height, info.HasHeight = v, true
+			}
+		case "preserveAspectRatio":
+			info.PreserveAspectRatio = attr.Value
+		}
+	}
+	if !info.HasWidth {
+		info.Width = info.ViewBox.W
+	}
+	if !info.HasHeight {
+		info.Height = info.ViewBox.H
+	}
+}
+
+// resolveLength parses an SVG length such as "100", "100px", "2.5in", or
+// "50%" into a value in user units, resolving percentages against ref
+// (typically the matching viewBox dimension).
+func resolveLength(v string, ref float64) (float64, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if strings.HasSuffix(v, "%") {
+		n, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		if err != nil {
+			return 0, false
+		}
+		return ref * n / 100, true
+	}
+	const (
+		pxPerPt = 96.0 / 72.0
+		pxPerMM = 96.0 / 25.4
+		pxPerIn = 96.0
+	)
+	for suffix, pxPerUnit := range map[string]float64{
+		"pt": pxPerPt,
+		"mm": pxPerMM,
+		"in": pxPerIn,
+		"px": 1,
+	} {
+		if strings.HasSuffix(v, suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(v, suffix), 64)
+			if err != nil {
+				return 0, false
+			}
+			return n * pxPerUnit, true
+		}
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+
+package oksvg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadIconInfoApplyToIntrinsicSize(t *testing.T) {
+	const src = `<svg viewBox="0 0 100 50" width="200px" height="25%" preserveAspectRatio="xMinYMax slice"></svg>`
+
+	info, err := ReadIconInfo(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadIconInfo: %v", err)
+	}
+
+	icon := &SvgIcon{}
+	info.ApplyTo(icon)
+
+	w, h, hasW, hasH := icon.IntrinsicSize()
+	if !hasW || w != 200 {
+		t.Errorf("width = %v, hasWidth = %v, want 200, true", w, hasW)
+	}
+	if !hasH || h != 12.5 {
+		t.Errorf("height = %v, hasHeight = %v, want 12.5, true", h, hasH)
+	}
+	if icon.ViewBox.W != 100 || icon.ViewBox.H != 50 {
+		t.Errorf("ViewBox = %+v, want W=100 H=50", icon.ViewBox)
+	}
+	if icon.PreserveAspectRatio.Align != AlignXMinYMax || icon.PreserveAspectRatio.Fit != FitSlice {
+		t.Errorf("PreserveAspectRatio = %+v, want {AlignXMinYMax FitSlice}", icon.PreserveAspectRatio)
+	}
+}
+
+func TestReadIconInfoWidthBeforeViewBox(t *testing.T) {
+	// width/height appear before viewBox here; XML attribute order is not
+	// guaranteed, so the percentage resolution must not depend on it.
+	const src = `<svg width="200px" height="25%" viewBox="0 0 100 50"></svg>`
+
+	info, err := ReadIconInfo(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadIconInfo: %v", err)
+	}
+
+	icon := &SvgIcon{}
+	info.ApplyTo(icon)
+
+	w, h, hasW, hasH := icon.IntrinsicSize()
+	if !hasW || w != 200 {
+		t.Errorf("width = %v, hasWidth = %v, want 200, true", w, hasW)
+	}
+	if !hasH || h != 12.5 {
+		t.Errorf("height = %v, hasHeight = %v, want 12.5, true", h, hasH)
+	}
+}
+
+func TestReadIconInfoApplyToNoWidthHeight(t *testing.T) {
+	const src = `<svg viewBox="0 0 24 24"></svg>`
+
+	info, err := ReadIconInfo(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadIconInfo: %v", err)
+	}
+
+	icon := &SvgIcon{}
+	info.ApplyTo(icon)
+
+	w, h, hasW, hasH := icon.IntrinsicSize()
+	if hasW || hasH {
+		t.Errorf("hasWidth = %v, hasHeight = %v, want both false", hasW, hasH)
+	}
+	if w != 24 || h != 24 {
+		t.Errorf("w, h = %v, %v, want 24, 24 (viewBox fallback)", w, h)
+	}
+	if icon.PreserveAspectRatio.Align != AlignXMidYMid || icon.PreserveAspectRatio.Fit != FitMeet {
+		t.Errorf("PreserveAspectRatio = %+v, want the spec default {AlignXMidYMid FitMeet}", icon.PreserveAspectRatio)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+
+package oksvg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/timskillman/rasterx"
+)
+
+func TestAddPathRecordsColorTokensFromCurrentColor(t *testing.T) {
+	icon := &SvgIcon{}
+	index := icon.AddPath(SvgPath{Style: PathStyle{FillColor: color.Black}}, "currentColor", "")
+
+	tokens, ok := icon.colorTokens[index]
+	if !ok {
+		t.Fatalf("colorTokens[%d] not recorded", index)
+	}
+	if tokens.Fill != "currentColor" || tokens.Stroke != "" {
+		t.Errorf("tokens = %+v, want Fill=currentColor, Stroke=\"\"", tokens)
+	}
+}
+
+func TestAddPathLeavesPlainColorsUntouched(t *testing.T) {
+	icon := &SvgIcon{}
+	index := icon.AddPath(SvgPath{Style: PathStyle{FillColor: color.Black}}, "#ff0000", "")
+	if _, ok := icon.colorTokens[index]; ok {
+		t.Errorf("colorTokens[%d] recorded for a plain color fill", index)
+	}
+}
+
+func TestDrawThemedRendersDifferentColorsPerTheme(t *testing.T) {
+	icon := &SvgIcon{}
+	icon.ViewBox.W, icon.ViewBox.H = 10, 10
+	var square rasterx.Path
+	square.Start(fxPoint(0, 0))
+	square.Line(fxPoint(10, 0))
+	square.Line(fxPoint(10, 10))
+	square.Line(fxPoint(0, 10))
+	square.Stop(true)
+	icon.AddPath(SvgPath{Path: square, Style: PathStyle{FillOpacity: 1, FillColor: color.Black}}, "currentColor", "")
+	icon.SetTarget(0, 0, 10, 10)
+
+	red := icon.AsImageThemed(10, 10, Theme{CurrentColor: color.RGBA{R: 255, A: 255}})
+	blue := icon.AsImageThemed(10, 10, Theme{CurrentColor: color.RGBA{B: 255, A: 255}})
+
+	if imagesIdentical(red, blue) {
+		t.Errorf("DrawThemed produced identical pixels for two different CurrentColor themes")
+	}
+}
+
+func imagesIdentical(a, b image.Image) bool {
+	bounds := a.Bounds()
+	if bounds != b.Bounds() {
+		return false
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if a.At(x, y) != b.At(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+// Copyright 2017 The oksvg Authors. All rights reserved.
+//
+// vector.go defines the Vector interface implemented by code generated
+// with cmd/oksvggen, letting a generated icon be fed into NewIcon without
+// any XML parsing at runtime.
+
+package oksvg
+
+import "github.com/timskillman/rasterx"
+
+// Vector builds an SvgIcon directly, bypassing XML/CSS parsing. Code
+// generated by cmd/oksvggen implements this interface once per source
+// SVG file.
+type Vector interface {
+	// Build populates icon with the viewBox, paths and transform that the
+	// generator captured from the source SVG.
+	Build(icon *SvgIcon)
+}
+
+// VectorFunc adapts a plain build function to the Vector interface, the
+// shape cmd/oksvggen emits for each icon.
+type VectorFunc func(icon *SvgIcon)
+
+// Build calls f(icon).
+func (f VectorFunc) Build(icon *SvgIcon) { f(icon) }
+
+// NewIcon constructs an SvgIcon from a generated Vector, with no XML
+// parsing involved. Use this with generated code as:
+//
+//	icon := oksvg.NewIcon(generated.MyLogo)
+//	img := icon.AsImage()
+func NewIcon(v Vector) *SvgIcon {
+	icon := &SvgIcon{
+		Grads:     map[string]*rasterx.Gradient{},
+		Defs:      map[string][]definition{},
+		Transform: rasterx.Identity,
+	}
+	v.Build(icon)
+	return icon
+}